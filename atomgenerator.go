@@ -3,12 +3,24 @@
 // The package is based on an implementation from Krzysztof Kowalczyk's
 // https://github.com/kjk/apptranslator, with some modifications:
 //
-// - Generate entry ids based on a scheme described on diveintomark.org,
-//   see `(e Entry) genId()`.
-// - Added <author>s to Feed and Entry.
-// - Added <content> field to Entry.
-// - Validate() to check whether the Feed conforms to Atom.
-// - Godoc.
+//   - Generate entry ids based on a scheme described on diveintomark.org,
+//     see `(e Entry) genId()`.
+//   - Added <author>s to Feed and Entry.
+//   - Added <content> field to Entry.
+//   - Added <category>, <contributor>, and <rights> to Feed and Entry.
+//   - Validate() to check whether the Feed conforms to Atom.
+//   - Added enclosures and iTunes/Google Play podcast metadata to Entry
+//     and Feed.
+//   - GenJSON() to generate a JSON Feed 1.1 representation alongside Atom.
+//   - Added Media RSS (media:group) support to Entry.
+//   - Added a streaming Writer, for generating large feeds without
+//     materializing every entry in memory.
+//   - Parse()/ParseBytes() to read an Atom 1.0 or 0.3 feed back into a
+//     Feed.
+//   - Support for multiple <link>s per Feed and Entry.
+//   - Support for text and xhtml content, and out-of-line content via
+//     src, beyond the original html-only <content>.
+//   - Godoc.
 //
 // http://www.atomenabled.org/developers/syndication and RFC 4287 were
 // used as a references.
@@ -40,15 +52,90 @@ type Feed struct {
 	// Required.
 	PubDate time.Time
 	Link    string
-	// Required unless all entries have at least one Author.
-	Authors []Author
-	entries []*Entry
+	// Optional. Conveys rights, e.g. copyright, held over the feed.
+	Rights string
+	// Set by Parse/ParseBytes to FormatAtom10 or FormatAtom03 to record
+	// which Atom version the feed was parsed from. Empty for feeds built
+	// directly rather than parsed.
+	Format string
+
+	authors        []Author
+	contributors   []Author
+	categories     []Category
+	links          []Link
+	entries        []*Entry
+	podcast        bool
+	podcastOptions PodcastOptions
 }
 
 func (f *Feed) AddEntry(e *Entry) {
 	f.entries = append(f.entries, e)
 }
 
+// Required unless all entries have at least one Author.
+func (f *Feed) AddAuthor(a Author) {
+	f.authors = append(f.authors, a)
+}
+
+// AddContributor adds a <contributor> to the feed.
+func (f *Feed) AddContributor(c Author) {
+	f.contributors = append(f.contributors, c)
+}
+
+// AddCategory adds a <category> to the feed.
+func (f *Feed) AddCategory(c Category) {
+	f.categories = append(f.categories, c)
+}
+
+// SetRights sets the feed's <rights> element.
+func (f *Feed) SetRights(rights string) {
+	f.Rights = rights
+}
+
+// AddLink adds a <link> to the feed, beyond the rel="alternate" link
+// generated from the Link field.
+func (f *Feed) AddLink(l Link) {
+	f.links = append(f.links, l)
+}
+
+// EnablePodcast turns on iTunes/Google Play podcast marshalling for the
+// feed and its entries. Without it, Entry.Podcast and any podcast-specific
+// fields are ignored.
+func (f *Feed) EnablePodcast(opts PodcastOptions) {
+	f.podcast = true
+	f.podcastOptions = opts
+}
+
+// Entries returns the feed's entries, e.g. as added by AddEntry or
+// produced by Parse/ParseBytes.
+func (f *Feed) Entries() []*Entry {
+	return f.entries
+}
+
+// Authors returns the feed's <author>s, as added by AddAuthor or
+// produced by Parse/ParseBytes.
+func (f *Feed) Authors() []Author {
+	return f.authors
+}
+
+// Contributors returns the feed's <contributor>s, as added by
+// AddContributor or produced by Parse/ParseBytes.
+func (f *Feed) Contributors() []Author {
+	return f.contributors
+}
+
+// Categories returns the feed's <category>s, as added by AddCategory or
+// produced by Parse/ParseBytes.
+func (f *Feed) Categories() []Category {
+	return f.categories
+}
+
+// Links returns the feed's <link>s beyond the rel="alternate" link
+// derived from Link, as added by AddLink or produced by Parse/ParseBytes.
+func (f *Feed) Links() []Link {
+	return f.links
+}
+
 type Author struct {
 	// Required.
 	Name string `xml:"name"`
@@ -58,6 +145,14 @@ type Author struct {
 	Uri string `xml:"uri,omitempty"`
 }
 
+// A Category as defined by RFC 4287 section 4.2.2. Term is required;
+// Scheme and Label are optional.
+type Category struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
 type Entry struct {
 	// Required.
 	Title string
@@ -66,40 +161,282 @@ type Entry struct {
 	Link        string
 	Description string
 	Content     string
+	// Optional. Conveys rights, e.g. copyright, held over the entry.
+	Rights string
+	// Optional. The type of Description and Content: TypeHTML (the
+	// default), TypeText, or TypeXHTML.
+	ContentType ContentType
+	// Optional. When set, Content's body is omitted and <content> points
+	// out-of-line via src=ContentSrc instead, per RFC 4287 section
+	// 4.1.3.1. Requires Description to be set; enforced in Validate().
+	ContentSrc string
+
 	// Required unless the Feed has at least one Author.
-	Authors []Author
+	authors      []Author
+	contributors []Author
+	categories   []Category
+	links        []Link
+	enclosures   []enclosure
+
+	// Optional. iTunes/Google Play podcast metadata, marshalled only if the
+	// Entry's Feed has podcast mode enabled via Feed.EnablePodcast.
+	Podcast EntryPodcast
+
+	// Optional. Media RSS (http://search.yahoo.com/mrss/) description for
+	// this entry's media:group.
+	MediaDescription string
+	mediaContents    []MediaContent
+	mediaThumbnails  []MediaThumbnail
+}
+
+// AddAuthor adds an <author> to the entry.
+func (e *Entry) AddAuthor(a Author) {
+	e.authors = append(e.authors, a)
+}
+
+// AddContributor adds a <contributor> to the entry.
+func (e *Entry) AddContributor(c Author) {
+	e.contributors = append(e.contributors, c)
+}
+
+// AddCategory adds a <category> to the entry.
+func (e *Entry) AddCategory(c Category) {
+	e.categories = append(e.categories, c)
+}
+
+// SetRights sets the entry's <rights> element.
+func (e *Entry) SetRights(rights string) {
+	e.Rights = rights
+}
+
+// AddLink adds a <link> to the entry, beyond the rel="alternate" link
+// generated from the Link field. Useful for e.g. rel="self", rel="hub",
+// rel="replies", or per-language alternates.
+func (e *Entry) AddLink(l Link) {
+	e.links = append(e.links, l)
+}
+
+// AddEnclosure adds an enclosure to the entry, marshalled as a
+// <link rel="enclosure"> per RFC 4287 section 4.2.7.2.
+func (e *Entry) AddEnclosure(url, mimeType string, length int64) {
+	e.enclosures = append(e.enclosures, enclosure{url, mimeType, length})
+}
+
+// AddMediaContent adds a Media RSS <media:content> to the entry's
+// media:group.
+func (e *Entry) AddMediaContent(c MediaContent) {
+	e.mediaContents = append(e.mediaContents, c)
+}
+
+// AddMediaThumbnail adds a Media RSS <media:thumbnail> to the entry's
+// media:group.
+func (e *Entry) AddMediaThumbnail(t MediaThumbnail) {
+	e.mediaThumbnails = append(e.mediaThumbnails, t)
+}
+
+// SetMediaDescription sets the entry's media:group <media:description>.
+func (e *Entry) SetMediaDescription(description string) {
+	e.MediaDescription = description
+}
+
+// Authors returns the entry's <author>s, as added by AddAuthor or
+// produced by Parse/ParseBytes.
+func (e *Entry) Authors() []Author {
+	return e.authors
+}
+
+// Contributors returns the entry's <contributor>s, as added by
+// AddContributor or produced by Parse/ParseBytes.
+func (e *Entry) Contributors() []Author {
+	return e.contributors
+}
+
+// Categories returns the entry's <category>s, as added by AddCategory or
+// produced by Parse/ParseBytes.
+func (e *Entry) Categories() []Category {
+	return e.categories
+}
+
+// Links returns the entry's <link>s beyond the rel="alternate" link
+// derived from Link, as added by AddLink or produced by Parse/ParseBytes.
+func (e *Entry) Links() []Link {
+	return e.links
 }
 
+func (e *Entry) hasMedia() bool {
+	return len(e.mediaContents) > 0 || len(e.mediaThumbnails) > 0 ||
+		len(e.MediaDescription) > 0
+}
+
+// ContentType is the type of an Entry's Description and Content, as
+// defined by RFC 4287 section 3.1.1.
+type ContentType int
+
+const (
+	// TypeHTML marshals the tag's text as escaped HTML chardata. This is
+	// the default, for backward compatibility.
+	TypeHTML ContentType = iota
+	// TypeText marshals the tag's text as escaped plain text.
+	TypeText
+	// TypeXHTML marshals the tag's text as an XML fragment, wrapped in
+	// the <div xmlns="http://www.w3.org/1999/xhtml"> RFC 4287 section
+	// 4.1.3.1 requires. The text must be well-formed XML.
+	TypeXHTML
+)
+
+func (t ContentType) String() string {
+	switch t {
+	case TypeText:
+		return "text"
+	case TypeXHTML:
+		return "xhtml"
+	default:
+		return "html"
+	}
+}
+
+const xhtmlNs = "http://www.w3.org/1999/xhtml"
+
+// xhtmlDiv is the <div xmlns="http://www.w3.org/1999/xhtml"> wrapper RFC
+// 4287 section 4.1.3.1 requires around xhtml content. Inner is tagged
+// ",innerxml" so it's written verbatim rather than escaped or reflowed.
+type xhtmlDiv struct {
+	XMLName xml.Name `xml:"div"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Inner   string   `xml:",innerxml"`
+}
+
+// typedTag is the shared shape of Entry's <summary> and <content>
+// elements: a Type attribute plus either chardata text or, for
+// TypeXHTML, an XML fragment; or, when Src is set, an empty,
+// out-of-line content element per RFC 4287 section 4.1.3.1.
 type typedTag struct {
-	S    string `xml:",chardata"`
-	Type string `xml:"type,attr"`
+	S    string
+	Type ContentType
+	Src  string
+}
+
+// MarshalXML implements xml.Marshaler so TypeXHTML content can be
+// emitted as a real XML fragment instead of escaped chardata.
+func (t typedTag) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "type"}, Value: t.Type.String()})
+
+	if len(t.Src) > 0 {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "src"}, Value: t.Src})
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if t.Type == TypeXHTML {
+		// Marshal the wrapping <div> via a ",innerxml" field rather than
+		// decoding and re-encoding t.S token by token: innerxml is
+		// written verbatim, so it round-trips byte-for-byte instead of
+		// picking up the enclosing encoder's indentation between
+		// sibling elements.
+		if err := enc.Encode(xhtmlDiv{Xmlns: xhtmlNs, Inner: t.S}); err != nil {
+			return err
+		}
+	} else if err := enc.EncodeToken(xml.CharData(t.S)); err != nil {
+		return err
+	}
+
+	return enc.EncodeToken(start.End())
 }
 
 type entryXml struct {
-	XMLName xml.Name `xml:"entry"`
-	Title   string   `xml:"title"`
-	Link    *linkXml
-	Updated string    `xml:"updated"`
-	Id      string    `xml:"id"`
-	Summary *typedTag `xml:"summary"`
-	Content *typedTag `xml:"content"`
-	Authors []Author
+	XMLName      xml.Name `xml:"entry"`
+	Title        string   `xml:"title"`
+	Links        []*linkXml
+	Updated      string     `xml:"updated"`
+	Id           string     `xml:"id"`
+	Summary      *typedTag  `xml:"summary"`
+	Content      *typedTag  `xml:"content"`
+	Rights       string     `xml:"rights,omitempty"`
+	Authors      []Author   `xml:"author"`
+	Contributors []Author   `xml:"contributor,omitempty"`
+	Categories   []Category `xml:"category,omitempty"`
+	podcastEntryXml
+	MediaGroup *mediaGroupXml `xml:"media:group,omitempty"`
+}
+
+// Link is an Atom <link> element, as defined by RFC 4287 section 4.2.7.
+// Add one to a Feed or Entry with AddLink.
+type Link struct {
+	// Required.
+	Href string
+	// Optional. "alternate", "self", "enclosure", "related", "via", "hub",
+	// etc. Defaults to "alternate" when empty.
+	Rel string
+	// Optional. The link's media type.
+	Type string
+	// Optional. The language of the linked resource.
+	HrefLang string
+	// Optional. Human-readable information about the link.
+	Title string
+	// Optional. An advisory length of the linked content, in bytes.
+	Length int64
+}
+
+func (l Link) toLinkXml() *linkXml {
+	rel := l.Rel
+	if len(rel) == 0 {
+		rel = "alternate"
+	}
+	return &linkXml{
+		Href:     l.Href,
+		Rel:      rel,
+		Type:     l.Type,
+		HrefLang: l.HrefLang,
+		Title:    l.Title,
+		Length:   l.Length,
+	}
+}
+
+// linkXmls builds the ordered list of <link> elements for a Feed or
+// Entry: the rel="alternate" link derived from its Link field, then any
+// links added with AddLink, then (for entries) any enclosures.
+func linkXmls(primaryHref string, extra []Link, encs []enclosure) []*linkXml {
+	var links []*linkXml
+	if len(primaryHref) > 0 {
+		links = append(links, &linkXml{Href: primaryHref, Rel: "alternate"})
+	}
+	for _, l := range extra {
+		links = append(links, l.toLinkXml())
+	}
+	for _, enc := range encs {
+		links = append(links, enc.toLinkXml())
+	}
+	return links
 }
 
 type linkXml struct {
-	XMLName xml.Name `xml:"link"`
-	Href    string   `xml:"href,attr"`
-	Rel     string   `xml:"rel,attr"`
+	XMLName  xml.Name `xml:"link"`
+	Href     string   `xml:"href,attr"`
+	Rel      string   `xml:"rel,attr"`
+	Type     string   `xml:"type,attr,omitempty"`
+	HrefLang string   `xml:"hreflang,attr,omitempty"`
+	Title    string   `xml:"title,attr,omitempty"`
+	Length   int64    `xml:"length,attr,omitempty"`
 }
 
 type feedXml struct {
-	XMLName xml.Name `xml:"feed"`
-	Ns      string   `xml:"xmlns,attr"`
-	Title   string   `xml:"title"`
-	Link    *linkXml
-	Id      string   `xml:"id"`
-	Updated string   `xml:"updated"`
-	Authors []Author `xml:"author"`
+	XMLName      xml.Name `xml:"feed"`
+	Ns           string   `xml:"xmlns,attr"`
+	Title        string   `xml:"title"`
+	Links        []*linkXml
+	Id           string     `xml:"id"`
+	Updated      string     `xml:"updated"`
+	Rights       string     `xml:"rights,omitempty"`
+	Authors      []Author   `xml:"author"`
+	Contributors []Author   `xml:"contributor,omitempty"`
+	Categories   []Category `xml:"category,omitempty"`
+	podcastFeedXml
+	MediaNs string `xml:"xmlns:media,attr,omitempty"`
 	Entries []*entryXml
 }
 
@@ -128,41 +465,75 @@ func (e *Entry) genId() string {
 	return b.String()
 }
 
-func newEntryXml(e *Entry) *entryXml {
+func newEntryXml(e *Entry, podcastEnabled bool) *entryXml {
 	x := &entryXml{
-		Id:      e.genId(),
-		Title:   e.Title,
-		Link:    &linkXml{Href: e.Link, Rel: "alternate"},
-		Updated: e.PubDate.Format(time.RFC3339)}
+		Id:           e.genId(),
+		Title:        e.Title,
+		Links:        linkXmls(e.Link, e.links, e.enclosures),
+		Updated:      e.PubDate.Format(time.RFC3339),
+		Rights:       e.Rights,
+		Authors:      e.authors,
+		Contributors: e.contributors,
+		Categories:   e.categories}
 
 	if len(e.Description) > 0 {
-		x.Summary = &typedTag{e.Description, "html"}
+		x.Summary = &typedTag{S: e.Description, Type: e.ContentType}
 	}
-	if len(e.Content) > 0 {
-		x.Content = &typedTag{e.Content, "html"}
+	if len(e.ContentSrc) > 0 {
+		x.Content = &typedTag{Type: e.ContentType, Src: e.ContentSrc}
+	} else if len(e.Content) > 0 {
+		x.Content = &typedTag{S: e.Content, Type: e.ContentType}
+	}
+	if podcastEnabled {
+		x.podcastEntryXml = newPodcastEntryXml(e.Podcast)
+	}
+	if e.hasMedia() {
+		x.MediaGroup = newMediaGroupXml(e)
 	}
 
 	return x
 }
 
-// Generate the final Atom feed in XML.
-func (f *Feed) GenXml() ([]byte, error) {
-	feed := &feedXml{
-		Ns:      ns,
-		Title:   f.Title,
-		Authors: f.Authors,
-		Link:    &linkXml{Href: f.Link, Rel: "alternate"},
-		Id:      f.Link,
-		Updated: f.PubDate.Format(time.RFC3339)}
+func (f *Feed) meta() FeedMeta {
+	m := FeedMeta{
+		Title:        f.Title,
+		PubDate:      f.PubDate,
+		Link:         f.Link,
+		Rights:       f.Rights,
+		Authors:      f.authors,
+		Contributors: f.contributors,
+		Categories:   f.categories,
+		Links:        f.links,
+	}
+	if f.podcast {
+		m.Podcast = &f.podcastOptions
+	}
 	for _, e := range f.entries {
-		feed.Entries = append(feed.Entries, newEntryXml(e))
+		if e.hasMedia() {
+			m.UsesMedia = true
+			break
+		}
 	}
-	data, err := xml.MarshalIndent(feed, " ", " ")
+	return m
+}
+
+// Generate the final Atom feed in XML. GenXml is a thin wrapper over
+// NewWriter/WriteEntry/Close for callers who don't need to stream.
+func (f *Feed) GenXml() ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, f.meta())
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
-	s := append([]byte(xml.Header[:len(xml.Header)-1]), data...)
-	return s, nil
+	for _, e := range f.entries {
+		if err := w.WriteEntry(e); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // Check if the feed conforms to the Atom standard. The check is fairly ok,
@@ -180,16 +551,16 @@ func (f *Feed) Validate() []error {
 	}
 
 	// Either the feed has an author, or all entries must have one.
-	if len(f.Authors) == 0 {
+	if len(f.authors) == 0 {
 		for _, e := range f.entries {
-			if len(e.Authors) == 0 {
+			if len(e.authors) == 0 {
 				errs = append(errs, fmt.Errorf(
 					"Feed has no authors, and entry %v has none either.", e.Title))
 			}
 		}
 	} else {
 		// All authors must have a name.
-		for i, author := range f.Authors {
+		for i, author := range f.authors {
 			if len(author.Name) == 0 {
 				errs = append(errs, fmt.Errorf(
 					"Feed author %v must have a Name.", i))
@@ -197,6 +568,13 @@ func (f *Feed) Validate() []error {
 		}
 	}
 
+	// Every category must have a Term.
+	for i, c := range f.categories {
+		if len(c.Term) == 0 {
+			errs = append(errs, fmt.Errorf("Feed category %v must have a Term.", i))
+		}
+	}
+
 	// Entries must have title, updated. Id is generated.
 	for i, e := range f.entries {
 		if len(e.Title) == 0 {
@@ -205,6 +583,16 @@ func (f *Feed) Validate() []error {
 		if e.PubDate.IsZero() {
 			errs = append(errs, fmt.Errorf("Entry %v must have a PubDate.", i))
 		}
+		if len(e.ContentSrc) > 0 && len(e.Description) == 0 {
+			errs = append(errs, fmt.Errorf(
+				"Entry %v has a ContentSrc, so it must also have a Description.", i))
+		}
+		for j, c := range e.categories {
+			if len(c.Term) == 0 {
+				errs = append(errs, fmt.Errorf(
+					"Entry %v category %v must have a Term.", i, j))
+			}
+		}
 	}
 
 	return errs
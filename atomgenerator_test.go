@@ -3,6 +3,7 @@ package atomgenerator
 import (
 	"bytes"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 )
@@ -136,3 +137,157 @@ func TestValidation(t *testing.T) {
 		t.Error("Expected an error for lack of Term in category.")
 	}
 }
+
+func TestMultipleLinks(t *testing.T) {
+	pubDate, _ := time.Parse("2006-01-02 15:04", "2008-09-10 11:12")
+
+	f := Feed{
+		Title:   "title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus",
+		authors: []Author{{Name: "author name"}},
+	}
+	f.AddLink(Link{Href: "http://www.myblog.bogus/feed.atom", Rel: "self"})
+
+	entry := &Entry{
+		Title:   "entry title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus/entry",
+	}
+	entry.AddLink(Link{Href: "http://www.myblog.bogus/entry.de", Rel: "alternate", HrefLang: "de"})
+	f.AddEntry(entry)
+
+	atom, err := f.GenXml()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte(`<?xml version="1.0" encoding="UTF-8"?> <feed xmlns="http://www.w3.org/2005/Atom">
+		  <title>title</title>
+		  <link href="http://www.myblog.bogus" rel="alternate"></link>
+		  <link href="http://www.myblog.bogus/feed.atom" rel="self"></link>
+		  <id>http://www.myblog.bogus</id>
+		  <updated>2008-09-10T11:12:00Z</updated>
+		  <author>
+		   <name>author name</name>
+		  </author>
+		  <entry>
+		   <title>entry title</title>
+		   <link href="http://www.myblog.bogus/entry" rel="alternate"></link>
+		   <link href="http://www.myblog.bogus/entry.de" rel="alternate" hreflang="de"></link>
+		   <updated>2008-09-10T11:12:00Z</updated>
+		   <id>tag:www.myblog.bogus,2008-09-10:/entry</id>
+		  </entry>
+		 </feed>`)
+
+	whitespace := regexp.MustCompile(`\s+`)
+	noWs := func(b []byte) []byte {
+		return whitespace.ReplaceAll(b, []byte(" "))
+	}
+	if !bytes.Equal(noWs(atom), noWs(expected)) {
+		t.Errorf("XML differs: expected %s, got %s.\n", expected, atom)
+	}
+
+	parsed, err := ParseBytes(atom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Links()) != 1 || parsed.Links()[0].Rel != "self" {
+		t.Errorf("expected feed's non-alternate link to round-trip, got %+v", parsed.Links())
+	}
+	if len(parsed.Entries()[0].Links()) != 1 || parsed.Entries()[0].Links()[0].HrefLang != "de" {
+		t.Errorf("expected entry's non-primary link to round-trip, got %+v", parsed.Entries()[0].Links())
+	}
+}
+
+func TestContentTypes(t *testing.T) {
+	pubDate, _ := time.Parse("2006-01-02 15:04", "2008-09-10 11:12")
+
+	f := Feed{Title: "title", PubDate: pubDate, Link: "http://www.myblog.bogus"}
+	f.AddAuthor(Author{Name: "author name"})
+
+	text := &Entry{
+		Title:       "text entry",
+		PubDate:     pubDate,
+		Link:        "http://www.myblog.bogus/text",
+		Content:     "plain <not a tag>",
+		ContentType: TypeText,
+	}
+	xhtml := &Entry{
+		Title:       "xhtml entry",
+		PubDate:     pubDate,
+		Link:        "http://www.myblog.bogus/xhtml",
+		Content:     "<b>bold</b><i>italic</i>plain",
+		ContentType: TypeXHTML,
+	}
+	outOfLine := &Entry{
+		Title:       "out-of-line entry",
+		PubDate:     pubDate,
+		Link:        "http://www.myblog.bogus/outofline",
+		Description: "a summary",
+		ContentSrc:  "http://www.myblog.bogus/outofline.html",
+	}
+	f.AddEntry(text)
+	f.AddEntry(xhtml)
+	f.AddEntry(outOfLine)
+
+	if errs := f.Validate(); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+
+	atom, err := f.GenXml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(atom)
+
+	if !strings.Contains(s, `<content type="text">plain &lt;not a tag&gt;</content>`) {
+		t.Errorf("TypeText content not escaped as expected: %s", s)
+	}
+	if !strings.Contains(s, `<div xmlns="http://www.w3.org/1999/xhtml"><b>bold</b><i>italic</i>plain</div>`) {
+		t.Errorf("TypeXHTML content not wrapped/passed through byte-for-byte: %s", s)
+	}
+	if !strings.Contains(s, `<content type="html" src="http://www.myblog.bogus/outofline.html"></content>`) {
+		t.Errorf("out-of-line content not marshalled as expected: %s", s)
+	}
+
+	parsed, err := ParseBytes(atom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pText := parsed.Entries()[0]
+	if pText.ContentType != TypeText || pText.Content != text.Content {
+		t.Errorf("expected TypeText entry to round-trip, got %+v", pText)
+	}
+	pXhtml := parsed.Entries()[1]
+	if pXhtml.ContentType != TypeXHTML || pXhtml.Content != xhtml.Content {
+		t.Errorf("expected TypeXHTML entry to round-trip, got %+v", pXhtml)
+	}
+
+	pe := parsed.Entries()[2]
+	if pe.ContentSrc != outOfLine.ContentSrc || pe.Content != "" {
+		t.Errorf("expected ContentSrc to round-trip and Content to stay empty, got %+v", pe)
+	}
+
+	// Re-emitting a parsed entry must not corrupt its content: an xhtml
+	// entry should come back out as xhtml, not escaped html.
+	reEmitted, err := parsed.GenXml()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reEmittedStr := string(reEmitted)
+	if !strings.Contains(reEmittedStr, `<content type="xhtml">`) {
+		t.Errorf("expected re-emitted xhtml entry to keep type=\"xhtml\", got %s", reEmittedStr)
+	}
+	if !strings.Contains(reEmittedStr, `<div xmlns="http://www.w3.org/1999/xhtml"><b>bold</b><i>italic</i>plain</div>`) {
+		t.Errorf("expected re-emitted xhtml entry to keep its markup unescaped, got %s", reEmittedStr)
+	}
+
+	missingDescription := &Entry{Title: "bad", PubDate: pubDate, ContentSrc: "http://www.myblog.bogus/bad.html"}
+	bad := Feed{Title: "title", PubDate: pubDate}
+	bad.AddAuthor(Author{Name: "a"})
+	bad.AddEntry(missingDescription)
+	if errs := bad.Validate(); len(errs) != 1 {
+		t.Errorf("expected an error for ContentSrc without Description, got %v", errs)
+	}
+}
@@ -0,0 +1,18 @@
+package atomgenerator
+
+// An enclosure attached to an Entry via Entry.AddEnclosure, e.g. an audio
+// or video file. Marshalled as a <link rel="enclosure">.
+type enclosure struct {
+	url      string
+	mimeType string
+	length   int64
+}
+
+func (enc enclosure) toLinkXml() *linkXml {
+	return &linkXml{
+		Href:   enc.url,
+		Rel:    "enclosure",
+		Type:   enc.mimeType,
+		Length: enc.length,
+	}
+}
@@ -0,0 +1,85 @@
+package atomgenerator
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeedXml struct {
+	Version     string       `json:"version"`
+	Title       string       `json:"title"`
+	HomePageURL string       `json:"home_page_url,omitempty"`
+	Authors     []jsonAuthor `json:"authors,omitempty"`
+	Items       []jsonItem   `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+type jsonItem struct {
+	Id            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	DateModified  string           `json:"date_modified,omitempty"`
+	Authors       []jsonAuthor     `json:"authors,omitempty"`
+	Attachments   []jsonAttachment `json:"attachments,omitempty"`
+}
+
+type jsonAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes,omitempty"`
+}
+
+func newJsonAuthors(authors []Author) []jsonAuthor {
+	var jas []jsonAuthor
+	for _, a := range authors {
+		jas = append(jas, jsonAuthor{Name: a.Name, URL: a.Uri})
+	}
+	return jas
+}
+
+func newJsonItem(e *Entry) jsonItem {
+	item := jsonItem{
+		Id:            e.genId(),
+		URL:           e.Link,
+		Title:         e.Title,
+		ContentHTML:   e.Content,
+		Summary:       e.Description,
+		DatePublished: e.PubDate.Format(time.RFC3339),
+		DateModified:  e.PubDate.Format(time.RFC3339),
+		Authors:       newJsonAuthors(e.authors),
+	}
+	for _, enc := range e.enclosures {
+		item.Attachments = append(item.Attachments, jsonAttachment{
+			URL:         enc.url,
+			MimeType:    enc.mimeType,
+			SizeInBytes: enc.length,
+		})
+	}
+	return item
+}
+
+// GenJSON generates the feed in JSON Feed 1.1 format
+// (https://jsonfeed.org/version/1.1), using the same Feed/Entry/Author
+// model as GenXml. Use Validate to check the feed before generating it;
+// the same validation rules apply to both formats.
+func (f *Feed) GenJSON() ([]byte, error) {
+	jf := &jsonFeedXml{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		Authors:     newJsonAuthors(f.authors),
+	}
+	for _, e := range f.entries {
+		jf.Items = append(jf.Items, newJsonItem(e))
+	}
+	return json.MarshalIndent(jf, "", " ")
+}
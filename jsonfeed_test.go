@@ -0,0 +1,59 @@
+package atomgenerator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestGenJSON(t *testing.T) {
+	pubDate, _ := time.Parse("2006-01-02 15:04", "2008-09-10 11:12")
+
+	f := Feed{
+		Title:   "title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus",
+		authors: []Author{{Name: "author name", Uri: "author uri"}},
+	}
+
+	entry := &Entry{
+		Title:       "entry title",
+		PubDate:     pubDate,
+		Link:        "http://www.myblog.bogus/entry",
+		Description: "entry summary",
+		Content:     "<p>entry content</p>",
+	}
+	entry.AddEnclosure("http://www.myblog.bogus/entry.mp3", "audio/mpeg", 12345)
+	f.AddEntry(entry)
+
+	b, err := f.GenJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jf jsonFeedXml
+	if err := json.Unmarshal(b, &jf); err != nil {
+		t.Fatal(err)
+	}
+
+	if jf.Version != jsonFeedVersion {
+		t.Errorf("expected version %q, got %q", jsonFeedVersion, jf.Version)
+	}
+	if jf.Title != "title" || jf.HomePageURL != "http://www.myblog.bogus" {
+		t.Errorf("unexpected feed-level fields: %+v", jf)
+	}
+	if len(jf.Authors) != 1 || jf.Authors[0].Name != "author name" || jf.Authors[0].URL != "author uri" {
+		t.Errorf("unexpected authors: %+v", jf.Authors)
+	}
+	if len(jf.Items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(jf.Items))
+	}
+	item := jf.Items[0]
+	if item.Title != "entry title" || item.Summary != "entry summary" || item.ContentHTML != "<p>entry content</p>" {
+		t.Errorf("unexpected item fields: %+v", item)
+	}
+	if len(item.Attachments) != 1 || item.Attachments[0].URL != "http://www.myblog.bogus/entry.mp3" ||
+		item.Attachments[0].MimeType != "audio/mpeg" || item.Attachments[0].SizeInBytes != 12345 {
+		t.Errorf("unexpected attachments: %+v", item.Attachments)
+	}
+}
@@ -0,0 +1,77 @@
+package atomgenerator
+
+const mediaNs = "http://search.yahoo.com/mrss/"
+
+// MediaContent is a Media RSS <media:content> item, as defined by
+// http://search.yahoo.com/mrss/.
+type MediaContent struct {
+	// Required.
+	URL string
+	// Optional. The MIME type, e.g. "video/mp4".
+	Type string
+	// Optional. "image", "audio", "video", "document", or "executable".
+	Medium string
+	// Optional, in pixels.
+	Width int
+	// Optional, in pixels.
+	Height int
+	// Optional, in seconds.
+	Duration int
+	// Optional, in bytes.
+	FileSize int64
+}
+
+// MediaThumbnail is a Media RSS <media:thumbnail>.
+type MediaThumbnail struct {
+	// Required.
+	URL string
+	// Optional, in pixels.
+	Width int
+	// Optional, in pixels.
+	Height int
+}
+
+type mediaContentXml struct {
+	URL      string `xml:"url,attr"`
+	Type     string `xml:"type,attr,omitempty"`
+	Medium   string `xml:"medium,attr,omitempty"`
+	Width    int    `xml:"width,attr,omitempty"`
+	Height   int    `xml:"height,attr,omitempty"`
+	Duration int    `xml:"duration,attr,omitempty"`
+	FileSize int64  `xml:"fileSize,attr,omitempty"`
+}
+
+type mediaThumbnailXml struct {
+	URL    string `xml:"url,attr"`
+	Width  int    `xml:"width,attr,omitempty"`
+	Height int    `xml:"height,attr,omitempty"`
+}
+
+type mediaGroupXml struct {
+	Contents    []mediaContentXml   `xml:"media:content,omitempty"`
+	Thumbnails  []mediaThumbnailXml `xml:"media:thumbnail,omitempty"`
+	Description string              `xml:"media:description,omitempty"`
+}
+
+func newMediaGroupXml(e *Entry) *mediaGroupXml {
+	g := &mediaGroupXml{Description: e.MediaDescription}
+	for _, c := range e.mediaContents {
+		g.Contents = append(g.Contents, mediaContentXml{
+			URL:      c.URL,
+			Type:     c.Type,
+			Medium:   c.Medium,
+			Width:    c.Width,
+			Height:   c.Height,
+			Duration: c.Duration,
+			FileSize: c.FileSize,
+		})
+	}
+	for _, t := range e.mediaThumbnails {
+		g.Thumbnails = append(g.Thumbnails, mediaThumbnailXml{
+			URL:    t.URL,
+			Width:  t.Width,
+			Height: t.Height,
+		})
+	}
+	return g
+}
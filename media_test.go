@@ -0,0 +1,63 @@
+package atomgenerator
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestMediaGroup(t *testing.T) {
+	pubDate, _ := time.Parse("2006-01-02 15:04", "2008-09-10 11:12")
+
+	f := Feed{
+		Title:   "title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus",
+		authors: []Author{{Name: "author name"}},
+	}
+
+	entry := &Entry{
+		Title:   "entry title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus/entry",
+	}
+	entry.AddMediaContent(MediaContent{URL: "http://www.myblog.bogus/video.mp4", Type: "video/mp4", Medium: "video", Width: 640, Height: 480})
+	entry.AddMediaThumbnail(MediaThumbnail{URL: "http://www.myblog.bogus/thumb.jpg", Width: 120, Height: 90})
+	entry.SetMediaDescription("media description")
+	f.AddEntry(entry)
+
+	atom, err := f.GenXml()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte(`<?xml version="1.0" encoding="UTF-8"?> <feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+		  <title>title</title>
+		  <link href="http://www.myblog.bogus" rel="alternate"></link>
+		  <id>http://www.myblog.bogus</id>
+		  <updated>2008-09-10T11:12:00Z</updated>
+		  <author>
+		   <name>author name</name>
+		  </author>
+		  <entry>
+		   <title>entry title</title>
+		   <link href="http://www.myblog.bogus/entry" rel="alternate"></link>
+		   <updated>2008-09-10T11:12:00Z</updated>
+		   <id>tag:www.myblog.bogus,2008-09-10:/entry</id>
+		   <media:group>
+		    <media:content url="http://www.myblog.bogus/video.mp4" type="video/mp4" medium="video" width="640" height="480"></media:content>
+		    <media:thumbnail url="http://www.myblog.bogus/thumb.jpg" width="120" height="90"></media:thumbnail>
+		    <media:description>media description</media:description>
+		   </media:group>
+		  </entry>
+		 </feed>`)
+
+	whitespace := regexp.MustCompile(`\s+`)
+	noWs := func(b []byte) []byte {
+		return whitespace.ReplaceAll(b, []byte(" "))
+	}
+	if !bytes.Equal(noWs(atom), noWs(expected)) {
+		t.Errorf("XML differs: expected %s, got %s.\n", expected, atom)
+	}
+}
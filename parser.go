@@ -0,0 +1,333 @@
+package atomgenerator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// The two Atom namespaces this package's parser understands.
+const (
+	atom10Ns = ns
+	atom03Ns = "http://purl.org/atom/ns#"
+)
+
+// Values for Feed.Format, set by Parse/ParseBytes to record which Atom
+// version a feed was parsed from.
+const (
+	FormatAtom10 = "1.0"
+	FormatAtom03 = "0.3"
+)
+
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	var t time.Time
+	var err error
+	for _, layout := range dateLayouts {
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+type rawLink struct {
+	Href     string `xml:"href,attr"`
+	Rel      string `xml:"rel,attr"`
+	Type     string `xml:"type,attr"`
+	HrefLang string `xml:"hreflang,attr"`
+	Title    string `xml:"title,attr"`
+	Length   int64  `xml:"length,attr"`
+}
+
+func (l rawLink) toLink() Link {
+	return Link{
+		Href:     l.Href,
+		Rel:      l.Rel,
+		Type:     l.Type,
+		HrefLang: l.HrefLang,
+		Title:    l.Title,
+		Length:   l.Length,
+	}
+}
+
+func (l rawLink) isAlternate() bool {
+	return l.Rel == "" || l.Rel == "alternate"
+}
+
+type rawAuthor struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+	Uri   string `xml:"uri"`
+	Url   string `xml:"url"` // used by some Atom 0.3 feeds instead of uri
+}
+
+func (a rawAuthor) toAuthor() Author {
+	uri := a.Uri
+	if len(uri) == 0 {
+		uri = a.Url
+	}
+	return Author{Name: a.Name, Email: a.Email, Uri: uri}
+}
+
+type rawCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr"`
+	Label  string `xml:"label,attr"`
+}
+
+func (c rawCategory) toCategory() Category {
+	return Category{Term: c.Term, Scheme: c.Scheme, Label: c.Label}
+}
+
+// rawContent captures a <content> or <summary> element in either its
+// Atom 1.0 shape (type="html"/"xhtml"/"text") or its Atom 0.3 shape
+// (mode="escaped"/"base64"/"xml").
+type rawContent struct {
+	Type     string `xml:"type,attr"`
+	Mode     string `xml:"mode,attr"`
+	Src      string `xml:"src,attr"`
+	CharData string `xml:",chardata"`
+	InnerXML string `xml:",innerxml"`
+}
+
+// decode normalizes a rawContent into a plain string, decoding Atom 0.3's
+// base64 mode and unwrapping Atom 1.0's xhtml <div>.
+func (rc rawContent) decode() (string, error) {
+	switch rc.Mode {
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(rc.CharData))
+		if err != nil {
+			return "", err
+		}
+		return string(decoded), nil
+	case "xml":
+		return strings.TrimSpace(rc.InnerXML), nil
+	}
+
+	if rc.Type == "xhtml" || rc.Type == "application/xhtml+xml" {
+		return stripXhtmlDiv(rc.InnerXML), nil
+	}
+	return rc.CharData, nil
+}
+
+// contentType maps a rawContent's Atom 1.0 type or Atom 0.3 mode to this
+// package's ContentType, so a parsed Entry re-emitted with GenXml keeps
+// its original type instead of defaulting to TypeHTML.
+func (rc rawContent) contentType() ContentType {
+	if rc.Mode == "xml" {
+		return TypeXHTML
+	}
+	switch rc.Type {
+	case "text":
+		return TypeText
+	case "xhtml", "application/xhtml+xml":
+		return TypeXHTML
+	default:
+		return TypeHTML
+	}
+}
+
+// stripXhtmlDiv unwraps the <div xmlns="http://www.w3.org/1999/xhtml">
+// wrapper that RFC 4287 section 4.1.3.1 requires around xhtml content.
+func stripXhtmlDiv(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "<div") {
+		return s
+	}
+	end := strings.Index(s, ">")
+	if end == -1 {
+		return s
+	}
+	s = strings.TrimSpace(s[end+1:])
+	return strings.TrimSpace(strings.TrimSuffix(s, "</div>"))
+}
+
+type rawEntry struct {
+	Title        string        `xml:"title"`
+	Links        []rawLink     `xml:"link"`
+	Updated      string        `xml:"updated"`   // Atom 1.0
+	Modified     string        `xml:"modified"`  // Atom 0.3
+	Published    string        `xml:"published"` // Atom 1.0
+	Issued       string        `xml:"issued"`    // Atom 0.3
+	Summary      rawContent    `xml:"summary"`
+	Content      rawContent    `xml:"content"`
+	Rights       string        `xml:"rights"`
+	Authors      []rawAuthor   `xml:"author"`
+	Contributors []rawAuthor   `xml:"contributor"`
+	Categories   []rawCategory `xml:"category"`
+}
+
+func (re rawEntry) alternateLink() string {
+	for _, l := range re.Links {
+		if l.isAlternate() {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+func (re rawEntry) toEntry() (*Entry, error) {
+	updated := re.Updated
+	if len(updated) == 0 {
+		updated = re.Modified
+	}
+	if len(updated) == 0 {
+		updated = re.Published
+	}
+	if len(updated) == 0 {
+		updated = re.Issued
+	}
+	pubDate, err := parseDate(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := re.Summary.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Entry{
+		Title:       re.Title,
+		PubDate:     pubDate,
+		Link:        re.alternateLink(),
+		Description: summary,
+		Rights:      re.Rights,
+		ContentType: re.Content.contentType(),
+	}
+	if len(re.Content.Src) > 0 {
+		e.ContentSrc = re.Content.Src
+	} else {
+		content, err := re.Content.decode()
+		if err != nil {
+			return nil, err
+		}
+		e.Content = content
+	}
+	usedAlternate := false
+	for _, l := range re.Links {
+		if l.isAlternate() && !usedAlternate {
+			usedAlternate = true
+			continue
+		}
+		e.AddLink(l.toLink())
+	}
+	for _, a := range re.Authors {
+		e.AddAuthor(a.toAuthor())
+	}
+	for _, c := range re.Contributors {
+		e.AddContributor(c.toAuthor())
+	}
+	for _, c := range re.Categories {
+		e.AddCategory(c.toCategory())
+	}
+	return e, nil
+}
+
+type rawFeed struct {
+	XMLName      xml.Name      `xml:"feed"`
+	Title        string        `xml:"title"`
+	Links        []rawLink     `xml:"link"`
+	Updated      string        `xml:"updated"`  // Atom 1.0
+	Modified     string        `xml:"modified"` // Atom 0.3
+	Rights       string        `xml:"rights"`
+	Authors      []rawAuthor   `xml:"author"`
+	Contributors []rawAuthor   `xml:"contributor"`
+	Categories   []rawCategory `xml:"category"`
+	Entries      []rawEntry    `xml:"entry"`
+}
+
+func (rf rawFeed) alternateLink() string {
+	for _, l := range rf.Links {
+		if l.isAlternate() {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// Parse reads an Atom feed, in either the 1.0
+// (http://www.w3.org/2005/Atom) or 0.3 (http://purl.org/atom/ns#)
+// namespace, and returns it using this package's Feed/Entry/Author types
+// so it can be mutated and re-emitted with GenXml. The returned Feed's
+// Format field is set to FormatAtom10 or FormatAtom03 to record which
+// version was parsed.
+func Parse(r io.Reader) (*Feed, error) {
+	var rf rawFeed
+	if err := xml.NewDecoder(r).Decode(&rf); err != nil {
+		return nil, err
+	}
+
+	var format string
+	switch rf.XMLName.Space {
+	case atom10Ns:
+		format = FormatAtom10
+	case atom03Ns:
+		format = FormatAtom03
+	default:
+		return nil, errors.New("atomgenerator: unrecognized feed namespace " + rf.XMLName.Space)
+	}
+
+	updated := rf.Updated
+	if len(updated) == 0 {
+		updated = rf.Modified
+	}
+	pubDate, err := parseDate(updated)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Feed{
+		Title:   rf.Title,
+		PubDate: pubDate,
+		Link:    rf.alternateLink(),
+		Rights:  rf.Rights,
+		Format:  format,
+	}
+	usedAlternate := false
+	for _, l := range rf.Links {
+		if l.isAlternate() && !usedAlternate {
+			usedAlternate = true
+			continue
+		}
+		f.AddLink(l.toLink())
+	}
+	for _, a := range rf.Authors {
+		f.AddAuthor(a.toAuthor())
+	}
+	for _, c := range rf.Contributors {
+		f.AddContributor(c.toAuthor())
+	}
+	for _, c := range rf.Categories {
+		f.AddCategory(c.toCategory())
+	}
+	for _, re := range rf.Entries {
+		e, err := re.toEntry()
+		if err != nil {
+			return nil, err
+		}
+		f.AddEntry(e)
+	}
+
+	return f, nil
+}
+
+// ParseBytes is a convenience wrapper around Parse for callers who
+// already have the feed in memory.
+func ParseBytes(b []byte) (*Feed, error) {
+	return Parse(bytes.NewReader(b))
+}
@@ -0,0 +1,83 @@
+package atomgenerator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	pubDate, _ := time.Parse("2006-01-02 15:04", "2008-09-10 11:12")
+
+	f := Feed{
+		Title:   "title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus",
+		authors: []Author{{Name: "author name", Email: "author email"}},
+	}
+	entry := &Entry{
+		Title:       "entry title",
+		PubDate:     pubDate,
+		Link:        "http://www.myblog.bogus/entry",
+		Description: "entry description",
+		Content:     "<p>entry content</p>",
+	}
+	entry.AddCategory(Category{Term: "entry category"})
+	f.AddEntry(entry)
+
+	atom, err := f.GenXml()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseBytes(atom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Format != FormatAtom10 {
+		t.Errorf("expected Format %q, got %q", FormatAtom10, parsed.Format)
+	}
+	if parsed.Title != f.Title || parsed.Link != f.Link || !parsed.PubDate.Equal(f.PubDate) {
+		t.Errorf("feed-level fields differ: got %+v", parsed)
+	}
+	if len(parsed.Authors()) != 1 || parsed.Authors()[0].Name != "author name" {
+		t.Errorf("unexpected authors: %+v", parsed.Authors())
+	}
+	if len(parsed.Entries()) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(parsed.Entries()))
+	}
+
+	pe := parsed.Entries()[0]
+	if pe.Title != entry.Title || pe.Link != entry.Link || pe.Description != entry.Description || pe.Content != entry.Content {
+		t.Errorf("entry fields differ: got %+v", pe)
+	}
+	if len(pe.Categories()) != 1 || pe.Categories()[0].Term != "entry category" {
+		t.Errorf("unexpected categories: %+v", pe.Categories())
+	}
+}
+
+func TestParseAtom03(t *testing.T) {
+	atom03 := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed version="0.3" xmlns="http://purl.org/atom/ns#">
+ <title>title</title>
+ <link rel="alternate" href="http://www.myblog.bogus"/>
+ <modified>2008-09-10T11:12:00Z</modified>
+ <entry>
+  <title>entry title</title>
+  <link rel="alternate" href="http://www.myblog.bogus/entry"/>
+  <issued>2008-09-10T11:12:00Z</issued>
+  <content type="text/html" mode="escaped">&lt;p&gt;entry content&lt;/p&gt;</content>
+ </entry>
+</feed>`)
+
+	f, err := ParseBytes(atom03)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Format != FormatAtom03 {
+		t.Errorf("expected Format %q, got %q", FormatAtom03, f.Format)
+	}
+	if len(f.Entries()) != 1 || f.Entries()[0].Content != "<p>entry content</p>" {
+		t.Errorf("unexpected entries: %+v", f.Entries())
+	}
+}
@@ -0,0 +1,129 @@
+package atomgenerator
+
+const itunesNs = "http://www.itunes.com/dtds/podcast-1.0.dtd"
+const googlePlayNs = "http://www.google.com/schemas/play-podcasts/1.0"
+
+// PodcastOptions configures the iTunes/Google Play podcast metadata for a
+// Feed. Enable it with Feed.EnablePodcast.
+type PodcastOptions struct {
+	// Optional. itunes:author / googleplay:author.
+	Author string
+	// Optional. itunes:subtitle.
+	Subtitle string
+	// Optional. itunes:summary / googleplay:description.
+	Summary string
+	// Optional. itunes:explicit / googleplay:explicit.
+	Explicit bool
+	// Optional. URL of the cover art, itunes:image / googleplay:image.
+	Image string
+	// Optional. The iTunes/Google Play category, e.g. "Technology".
+	Category string
+}
+
+// EntryPodcast carries the per-episode iTunes/Google Play metadata for an
+// Entry. It's only marshalled when the entry's Feed has podcast mode
+// enabled via Feed.EnablePodcast.
+type EntryPodcast struct {
+	PodcastOptions
+	// Optional. The episode duration, formatted as iTunes expects, e.g.
+	// "1:02:03" or a plain number of seconds.
+	Duration string
+}
+
+type hrefXml struct {
+	Href string `xml:"href,attr"`
+}
+
+type textAttrXml struct {
+	Text string `xml:"text,attr"`
+}
+
+// The iTunes/Google Play elements marshalled at the feed level when
+// Feed.EnablePodcast has been called.
+type podcastFeedXml struct {
+	ItunesNs     string `xml:"xmlns:itunes,attr,omitempty"`
+	GooglePlayNs string `xml:"xmlns:googleplay,attr,omitempty"`
+
+	ItunesAuthor   string       `xml:"itunes:author,omitempty"`
+	ItunesSubtitle string       `xml:"itunes:subtitle,omitempty"`
+	ItunesSummary  string       `xml:"itunes:summary,omitempty"`
+	ItunesExplicit string       `xml:"itunes:explicit,omitempty"`
+	ItunesImage    *hrefXml     `xml:"itunes:image,omitempty"`
+	ItunesCategory *textAttrXml `xml:"itunes:category,omitempty"`
+
+	GooglePlayAuthor      string       `xml:"googleplay:author,omitempty"`
+	GooglePlayDescription string       `xml:"googleplay:description,omitempty"`
+	GooglePlayExplicit    string       `xml:"googleplay:explicit,omitempty"`
+	GooglePlayImage       *hrefXml     `xml:"googleplay:image,omitempty"`
+	GooglePlayCategory    *textAttrXml `xml:"googleplay:category,omitempty"`
+}
+
+// The iTunes/Google Play elements marshalled on an entry when its Feed has
+// podcast mode enabled.
+type podcastEntryXml struct {
+	ItunesAuthor   string       `xml:"itunes:author,omitempty"`
+	ItunesSubtitle string       `xml:"itunes:subtitle,omitempty"`
+	ItunesSummary  string       `xml:"itunes:summary,omitempty"`
+	ItunesExplicit string       `xml:"itunes:explicit,omitempty"`
+	ItunesImage    *hrefXml     `xml:"itunes:image,omitempty"`
+	ItunesCategory *textAttrXml `xml:"itunes:category,omitempty"`
+	ItunesDuration string       `xml:"itunes:duration,omitempty"`
+
+	GooglePlayAuthor      string       `xml:"googleplay:author,omitempty"`
+	GooglePlayDescription string       `xml:"googleplay:description,omitempty"`
+	GooglePlayExplicit    string       `xml:"googleplay:explicit,omitempty"`
+	GooglePlayImage       *hrefXml     `xml:"googleplay:image,omitempty"`
+	GooglePlayCategory    *textAttrXml `xml:"googleplay:category,omitempty"`
+}
+
+func explicitStr(explicit bool) string {
+	if explicit {
+		return "yes"
+	}
+	return "no"
+}
+
+func newPodcastFeedXml(opts PodcastOptions) podcastFeedXml {
+	x := podcastFeedXml{
+		ItunesNs:              itunesNs,
+		GooglePlayNs:          googlePlayNs,
+		ItunesAuthor:          opts.Author,
+		ItunesSubtitle:        opts.Subtitle,
+		ItunesSummary:         opts.Summary,
+		ItunesExplicit:        explicitStr(opts.Explicit),
+		GooglePlayAuthor:      opts.Author,
+		GooglePlayDescription: opts.Summary,
+		GooglePlayExplicit:    explicitStr(opts.Explicit),
+	}
+	if len(opts.Image) > 0 {
+		x.ItunesImage = &hrefXml{Href: opts.Image}
+		x.GooglePlayImage = &hrefXml{Href: opts.Image}
+	}
+	if len(opts.Category) > 0 {
+		x.ItunesCategory = &textAttrXml{Text: opts.Category}
+		x.GooglePlayCategory = &textAttrXml{Text: opts.Category}
+	}
+	return x
+}
+
+func newPodcastEntryXml(p EntryPodcast) podcastEntryXml {
+	x := podcastEntryXml{
+		ItunesAuthor:          p.Author,
+		ItunesSubtitle:        p.Subtitle,
+		ItunesSummary:         p.Summary,
+		ItunesExplicit:        explicitStr(p.Explicit),
+		ItunesDuration:        p.Duration,
+		GooglePlayAuthor:      p.Author,
+		GooglePlayDescription: p.Summary,
+		GooglePlayExplicit:    explicitStr(p.Explicit),
+	}
+	if len(p.Image) > 0 {
+		x.ItunesImage = &hrefXml{Href: p.Image}
+		x.GooglePlayImage = &hrefXml{Href: p.Image}
+	}
+	if len(p.Category) > 0 {
+		x.ItunesCategory = &textAttrXml{Text: p.Category}
+		x.GooglePlayCategory = &textAttrXml{Text: p.Category}
+	}
+	return x
+}
@@ -0,0 +1,68 @@
+package atomgenerator
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestEnclosureAndPodcast(t *testing.T) {
+	pubDate, _ := time.Parse("2006-01-02 15:04", "2008-09-10 11:12")
+
+	f := Feed{
+		Title:   "title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus",
+		authors: []Author{{Name: "author name"}},
+	}
+	f.EnablePodcast(PodcastOptions{Author: "feed author", Explicit: true})
+
+	entry := &Entry{
+		Title:   "entry title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus/entry",
+		Podcast: EntryPodcast{PodcastOptions: PodcastOptions{Summary: "episode summary"}, Duration: "1:02:03"},
+	}
+	entry.AddEnclosure("http://www.myblog.bogus/entry.mp3", "audio/mpeg", 12345)
+	f.AddEntry(entry)
+
+	atom, err := f.GenXml()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte(`<?xml version="1.0" encoding="UTF-8"?> <feed xmlns="http://www.w3.org/2005/Atom" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:googleplay="http://www.google.com/schemas/play-podcasts/1.0">
+		  <title>title</title>
+		  <link href="http://www.myblog.bogus" rel="alternate"></link>
+		  <id>http://www.myblog.bogus</id>
+		  <updated>2008-09-10T11:12:00Z</updated>
+		  <author>
+		   <name>author name</name>
+		  </author>
+		  <itunes:author>feed author</itunes:author>
+		  <itunes:explicit>yes</itunes:explicit>
+		  <googleplay:author>feed author</googleplay:author>
+		  <googleplay:explicit>yes</googleplay:explicit>
+		  <entry>
+		   <title>entry title</title>
+		   <link href="http://www.myblog.bogus/entry" rel="alternate"></link>
+		   <link href="http://www.myblog.bogus/entry.mp3" rel="enclosure" type="audio/mpeg" length="12345"></link>
+		   <updated>2008-09-10T11:12:00Z</updated>
+		   <id>tag:www.myblog.bogus,2008-09-10:/entry</id>
+		   <itunes:summary>episode summary</itunes:summary>
+		   <itunes:explicit>no</itunes:explicit>
+		   <itunes:duration>1:02:03</itunes:duration>
+		   <googleplay:description>episode summary</googleplay:description>
+		   <googleplay:explicit>no</googleplay:explicit>
+		  </entry>
+		 </feed>`)
+
+	whitespace := regexp.MustCompile(`\s+`)
+	noWs := func(b []byte) []byte {
+		return whitespace.ReplaceAll(b, []byte(" "))
+	}
+	if !bytes.Equal(noWs(atom), noWs(expected)) {
+		t.Errorf("XML differs: expected %s, got %s.\n", expected, atom)
+	}
+}
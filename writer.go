@@ -0,0 +1,111 @@
+package atomgenerator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"time"
+)
+
+// FeedMeta carries the feed-level data needed by NewWriter. It mirrors
+// the fields a Feed marshals before its entries.
+type FeedMeta struct {
+	// Required.
+	Title string
+	// Required.
+	PubDate time.Time
+	Link    string
+	// Optional.
+	Rights       string
+	Authors      []Author
+	Contributors []Author
+	Categories   []Category
+	// Optional. Additional links beyond the rel="alternate" link derived
+	// from Link.
+	Links []Link
+	// Optional. Enables iTunes/Google Play podcast elements, as
+	// Feed.EnablePodcast does for GenXml.
+	Podcast *PodcastOptions
+	// UsesMedia must be set to true if any entry later passed to
+	// WriteEntry carries Media RSS fields (see Entry.AddMediaContent).
+	// Unlike GenXml, the Writer streams entries as they arrive and can't
+	// look ahead to discover this on its own.
+	UsesMedia bool
+}
+
+// A Writer streams an Atom feed to an io.Writer one entry at a time,
+// instead of materializing every entry in memory like GenXml does. Create
+// one with NewWriter, add entries with WriteEntry, and finish with
+// Close.
+type Writer struct {
+	w              io.Writer
+	podcastEnabled bool
+	closed         bool
+}
+
+// NewWriter writes the feed's header --- the XML declaration, the feed
+// metadata, and the author/contributor/category block --- and returns a
+// Writer ready to stream entries via WriteEntry.
+func NewWriter(w io.Writer, meta FeedMeta) (*Writer, error) {
+	feed := &feedXml{
+		Ns:           ns,
+		Title:        meta.Title,
+		Rights:       meta.Rights,
+		Authors:      meta.Authors,
+		Contributors: meta.Contributors,
+		Categories:   meta.Categories,
+		Links:        linkXmls(meta.Link, meta.Links, nil),
+		Id:           meta.Link,
+		Updated:      meta.PubDate.Format(time.RFC3339)}
+	if meta.Podcast != nil {
+		feed.podcastFeedXml = newPodcastFeedXml(*meta.Podcast)
+	}
+	if meta.UsesMedia {
+		feed.MediaNs = mediaNs
+	}
+
+	data, err := xml.MarshalIndent(feed, " ", " ")
+	if err != nil {
+		return nil, err
+	}
+	closeTag := []byte("</feed>")
+	if !bytes.HasSuffix(data, closeTag) {
+		return nil, errors.New("atomgenerator: unexpected feed encoding")
+	}
+	header := bytes.TrimRight(data[:len(data)-len(closeTag)], " \t\n")
+
+	if _, err := w.Write([]byte(xml.Header[:len(xml.Header)-1])); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &Writer{w: w, podcastEnabled: meta.Podcast != nil}, nil
+}
+
+// WriteEntry marshals and streams a single entry to the underlying
+// io.Writer.
+func (wr *Writer) WriteEntry(e *Entry) error {
+	data, err := xml.MarshalIndent(newEntryXml(e, wr.podcastEnabled), "  ", " ")
+	if err != nil {
+		return err
+	}
+	if _, err := wr.w.Write([]byte("\n")); err != nil {
+		return err
+	}
+	_, err = wr.w.Write(data)
+	return err
+}
+
+// Close writes the closing </feed> tag. It does not close the underlying
+// io.Writer.
+func (wr *Writer) Close() error {
+	if wr.closed {
+		return nil
+	}
+	wr.closed = true
+	_, err := wr.w.Write([]byte("\n </feed>"))
+	return err
+}
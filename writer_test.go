@@ -0,0 +1,51 @@
+package atomgenerator
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriterMatchesGenXml(t *testing.T) {
+	pubDate, _ := time.Parse("2006-01-02 15:04", "2008-09-10 11:12")
+
+	f := Feed{
+		Title:   "title",
+		PubDate: pubDate,
+		Link:    "http://www.myblog.bogus",
+		authors: []Author{{Name: "author name"}},
+	}
+	entry := &Entry{
+		Title:       "entry title",
+		PubDate:     pubDate,
+		Link:        "http://www.myblog.bogus/entry",
+		Description: "entry description",
+	}
+	f.AddEntry(entry)
+
+	want, err := f.GenXml()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, f.meta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteEntry(entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Writer output differs from GenXml: got %s, want %s", buf.Bytes(), want)
+	}
+
+	// Close must be idempotent.
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close returned an error: %v", err)
+	}
+}